@@ -0,0 +1,49 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package lsctx carries per-session values that are negotiated once,
+// during `initialize`, but are needed by handlers for every request
+// that follows. It is named lsctx (rather than context) to avoid
+// shadowing the standard library package at import sites.
+package lsctx
+
+import (
+	"context"
+
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+)
+
+type ctxKey int
+
+const (
+	clientCapsKey ctxKey = iota
+	rootDirKey
+)
+
+// WithClientCapabilities returns a copy of ctx carrying the
+// capabilities the client advertised in its `initialize` request.
+func WithClientCapabilities(ctx context.Context, caps *lsp.ClientCapabilities) context.Context {
+	return context.WithValue(ctx, clientCapsKey, caps)
+}
+
+// ClientCapabilities returns the capabilities stored by
+// WithClientCapabilities, if any.
+func ClientCapabilities(ctx context.Context) (*lsp.ClientCapabilities, bool) {
+	caps, ok := ctx.Value(clientCapsKey).(*lsp.ClientCapabilities)
+	return caps, ok
+}
+
+// WithRootDirectory returns a copy of ctx carrying the workspace root
+// directory URI the client supplied as `rootUri`.
+func WithRootDirectory(ctx context.Context, uri string) context.Context {
+	return context.WithValue(ctx, rootDirKey, uri)
+}
+
+// RootDirectory returns the root directory URI stored by
+// WithRootDirectory, if any.
+func RootDirectory(ctx context.Context) (string, bool) {
+	uri, ok := ctx.Value(rootDirKey).(string)
+	return uri, ok
+}
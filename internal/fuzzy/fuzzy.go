@@ -0,0 +1,116 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package fuzzy implements gopls-style fuzzy matching of candidate
+// strings against a user-typed query. Unlike a plain substring or
+// prefix filter, the query characters only need to appear in order as
+// a subsequence of the candidate, so "mbcu" matches `myblock "custom"`.
+package fuzzy
+
+import (
+	"math"
+	"strings"
+)
+
+// NoMatch is returned by Matcher.Score when the query does not occur
+// as a subsequence of the candidate at all. It is math.MinInt because
+// the gap penalty can drive a real match's score below zero.
+const NoMatch = math.MinInt
+
+const (
+	scoreSegmentStart = 8
+	scoreAfterSep     = 6
+	scoreCamelBound   = 4
+	scoreConsecutive  = 2
+	scoreDefault      = 1
+	gapPenalty        = 1
+)
+
+// Matcher scores candidate strings against a single query. It is cheap
+// to construct and safe to reuse across many candidates, which is the
+// expected usage when ranking the symbols of a large workspace.
+type Matcher struct {
+	query         string
+	caseSensitive bool
+}
+
+// NewMatcher builds a Matcher for query. Matching is case-insensitive
+// unless caseSensitive is true.
+func NewMatcher(query string, caseSensitive bool) *Matcher {
+	q := query
+	if !caseSensitive {
+		q = strings.ToLower(query)
+	}
+	return &Matcher{query: q, caseSensitive: caseSensitive}
+}
+
+// Score returns a ranking score for candidate, where a higher score
+// indicates a closer match. It returns NoMatch if the query is not a
+// subsequence of candidate. An empty query matches every candidate
+// with a score of 0.
+func (m *Matcher) Score(candidate string) int {
+	if m.query == "" {
+		return 0
+	}
+
+	cand := candidate
+	if !m.caseSensitive {
+		cand = strings.ToLower(candidate)
+	}
+
+	score := 0
+	queryIdx := 0
+	prevMatched := false
+	atSegmentStart := true
+	gap := 0
+
+	for candIdx := 0; candIdx < len(cand) && queryIdx < len(m.query); candIdx++ {
+		if cand[candIdx] == m.query[queryIdx] {
+			switch {
+			case atSegmentStart:
+				score += scoreSegmentStart
+			case candIdx > 0 && isSeparator(cand[candIdx-1]):
+				score += scoreAfterSep
+			case candIdx > 0 && isCamelBoundary(candidate, candIdx):
+				score += scoreCamelBound
+			case prevMatched:
+				score += scoreConsecutive
+			default:
+				score += scoreDefault
+			}
+			score -= gap * gapPenalty
+			gap = 0
+			prevMatched = true
+			queryIdx++
+		} else {
+			prevMatched = false
+			if queryIdx > 0 {
+				gap++
+			}
+		}
+		atSegmentStart = isSeparator(cand[candIdx])
+	}
+
+	if queryIdx < len(m.query) {
+		return NoMatch
+	}
+	return score
+}
+
+func isSeparator(b byte) bool {
+	switch b {
+	case '_', '-', '.', '"', '/', ' ':
+		return true
+	}
+	return false
+}
+
+func isCamelBoundary(s string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	prev, cur := s[i-1], s[i]
+	return prev >= 'a' && prev <= 'z' && cur >= 'A' && cur <= 'Z'
+}
@@ -0,0 +1,68 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fuzzy
+
+import "testing"
+
+func TestMatcher_Score_outOfOrderChars(t *testing.T) {
+	m := NewMatcher("mbcu", false)
+
+	if score := m.Score(`myblock "custom"`); score == NoMatch {
+		t.Fatalf("expected %q to match %q", "mbcu", `myblock "custom"`)
+	}
+
+	if score := m.Score(`provider "github"`); score != NoMatch {
+		t.Fatalf("expected %q not to match %q, got score %d", "mbcu", `provider "github"`, score)
+	}
+}
+
+func TestMatcher_Score_provghMatchesGithubOnly(t *testing.T) {
+	m := NewMatcher("provgh", false)
+
+	ghScore := m.Score(`provider "github"`)
+	googleScore := m.Score(`provider "google"`)
+
+	if ghScore == NoMatch || googleScore != NoMatch {
+		t.Fatalf("expected %q to match provider \"github\" only, got github=%d google=%d", "provgh", ghScore, googleScore)
+	}
+}
+
+func TestMatcher_Score_shorterNameScoresAtLeastAsHigh(t *testing.T) {
+	m := NewMatcher("myb", false)
+
+	shortScore := m.Score(`myblock "custom"`)
+	longScore := m.Score(`myblockish "custom" block`)
+
+	if shortScore == NoMatch || longScore == NoMatch {
+		t.Fatalf("expected both candidates to match, got short=%d long=%d", shortScore, longScore)
+	}
+
+	if shortScore < longScore {
+		t.Fatalf("expected shorter candidate to score at least as high as longer one, got short=%d long=%d", shortScore, longScore)
+	}
+}
+
+func TestMatcher_Score_emptyQueryMatchesEverything(t *testing.T) {
+	m := NewMatcher("", false)
+
+	for _, candidate := range []string{"", "anything", `provider "github"`} {
+		if score := m.Score(candidate); score != 0 {
+			t.Fatalf("expected empty query to score 0 for %q, got %d", candidate, score)
+		}
+	}
+}
+
+func TestMatcher_Score_caseSensitive(t *testing.T) {
+	m := NewMatcher("GH", true)
+
+	if score := m.Score(`provider "github"`); score != NoMatch {
+		t.Fatalf("expected case-sensitive query %q not to match lowercase candidate, got score %d", "GH", score)
+	}
+
+	if score := m.Score(`provider "GHub"`); score == NoMatch {
+		t.Fatalf("expected case-sensitive query %q to match %q", "GH", `provider "GHub"`)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/settings"
+)
+
+// Initialize implements the `initialize` request. It decodes the
+// client's initializationOptions into settings.Settings and stores
+// the decoded settings, the client's capabilities, and the workspace
+// root URI on the session context, so that every later request on
+// this session (e.g. workspace/symbol) can retrieve them via
+// settings.FromContext, lsctx.ClientCapabilities, and
+// lsctx.RootDirectory.
+func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams) (lsp.InitializeResult, error) {
+	svc.sessCtx = lsctx.WithClientCapabilities(svc.sessCtx, &params.Capabilities)
+	svc.sessCtx = settings.WithContext(svc.sessCtx, settings.Decode(params.InitializationOptions))
+	svc.sessCtx = lsctx.WithRootDirectory(svc.sessCtx, string(params.RootURI))
+
+	return lsp.InitializeResult{
+		Capabilities: lsp.ServerCapabilities{
+			// ResolveProvider must be advertised here, not just read
+			// from the client's capabilities: a spec-compliant client
+			// only calls workspaceSymbol/resolve once the server's own
+			// initialize response asks for it.
+			WorkspaceSymbolProvider: lsp.WorkspaceSymbolOptions{
+				ResolveProvider: true,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,353 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
+	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/fuzzy"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/settings"
+)
+
+// workspaceSymbolResolveProperty is the only resolvable property this
+// server currently advertises support for.
+const workspaceSymbolResolveProperty = "location.range"
+
+// workspaceSymbol is an unfiltered candidate gathered from a block,
+// top-level or nested, in an indexed document, before it is matched
+// and scored against a query.
+type workspaceSymbol struct {
+	name          string
+	kind          lsp.SymbolKind
+	containerName string
+	deprecated    bool
+	location      lsp.Location
+}
+
+// workspaceSymbolLocation is the LSP 3.17 `location` shape used by a
+// lightweight WorkspaceSymbol: a bare URI, with the range omitted
+// until the client resolves it.
+type workspaceSymbolLocation struct {
+	URI   lsp.DocumentURI `json:"uri"`
+	Range *lsp.Range      `json:"range,omitempty"`
+}
+
+// workspaceSymbolLight is the LSP 3.17 `WorkspaceSymbol` shape
+// returned to clients that advertised
+// `workspace.symbol.resolveSupport` for `location.range`. It omits the
+// range from the response so that huge workspaces don't serialize the
+// range of every candidate up front; the documents are still parsed to
+// collect the candidates.
+type workspaceSymbolLight struct {
+	Name          string                  `json:"name"`
+	Kind          lsp.SymbolKind          `json:"kind"`
+	Tags          []lsp.SymbolTag         `json:"tags,omitempty"`
+	ContainerName string                  `json:"containerName,omitempty"`
+	Location      workspaceSymbolLocation `json:"location"`
+}
+
+// WorkspaceSymbol implements the `workspace/symbol` request. It
+// collects every block, including nested blocks, across all indexed
+// documents, scores each candidate name against the query using the
+// client's configured symbolMatcher, and returns the matches sorted by
+// descending score.
+//
+// Clients that advertised `workspace.symbol.resolveSupport` for
+// `location.range` receive the lightweight LSP 3.17 shape, with the
+// range left for `workspaceSymbol/resolve` to fill in on demand.
+// Older clients continue to receive the full `SymbolInformation`
+// response, range included, as before.
+func (svc *service) WorkspaceSymbol(ctx context.Context, params lsp.WorkspaceSymbolParams) (interface{}, error) {
+	candidates, err := svc.collectWorkspaceSymbols(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := settings.FromContext(ctx)
+	matched := matchWorkspaceSymbols(candidates, params.Query, cfg.SymbolMatcher, cfg.SymbolMatcherMinScore)
+	tagSupport := clientSupportsDeprecatedTag(ctx)
+
+	if clientSupportsWorkspaceSymbolResolve(ctx) {
+		symbols := make([]workspaceSymbolLight, len(matched))
+		for i, c := range matched {
+			symbols[i] = workspaceSymbolLight{
+				Name:          c.name,
+				Kind:          c.kind,
+				Tags:          deprecationTags(c, tagSupport),
+				ContainerName: c.containerName,
+				Location:      workspaceSymbolLocation{URI: c.location.URI},
+			}
+		}
+		return symbols, nil
+	}
+
+	symbols := make([]lsp.SymbolInformation, len(matched))
+	for i, c := range matched {
+		symbols[i] = lsp.SymbolInformation{
+			Name:     c.name,
+			Kind:     c.kind,
+			Tags:     deprecationTags(c, tagSupport),
+			Location: c.location,
+		}
+	}
+	return symbols, nil
+}
+
+// deprecationTags returns the `tags` to advertise for c, or nil if c
+// isn't deprecated or the client never declared tagSupport for the
+// Deprecated tag value.
+func deprecationTags(c workspaceSymbol, tagSupport bool) []lsp.SymbolTag {
+	if !c.deprecated || !tagSupport {
+		return nil
+	}
+	return []lsp.SymbolTag{lsp.SymbolTagDeprecated}
+}
+
+// clientSupportsDeprecatedTag reports whether the client listed the
+// Deprecated tag in `workspace.symbol.tagSupport.valueSet` of its
+// `initialize` request.
+func clientSupportsDeprecatedTag(ctx context.Context) bool {
+	caps, ok := lsctx.ClientCapabilities(ctx)
+	if !ok || caps.Workspace == nil || caps.Workspace.Symbol == nil {
+		return false
+	}
+	tagSupport := caps.Workspace.Symbol.TagSupport
+	if tagSupport == nil {
+		return false
+	}
+	for _, tag := range tagSupport.ValueSet {
+		if tag == lsp.SymbolTagDeprecated {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkspaceSymbolResolve implements `workspaceSymbol/resolve`. It
+// re-parses the symbol's document on demand to compute the full range
+// that was left out of the lightweight item returned by
+// `workspace/symbol`.
+func (svc *service) WorkspaceSymbolResolve(ctx context.Context, item workspaceSymbolLight) (workspaceSymbolLight, error) {
+	handle := document.HandleFromURI(string(item.Location.URI))
+	doc, err := svc.stateStore.DocumentStore.GetDocument(handle)
+	if err != nil {
+		return item, fmt.Errorf("failed to resolve workspace symbol %q: %w", item.Name, err)
+	}
+
+	blockSymbols, err := svc.blockSymbolsInDocument(ctx, doc)
+	if err != nil {
+		return item, fmt.Errorf("failed to resolve workspace symbol %q: %w", item.Name, err)
+	}
+
+	for _, s := range blockSymbols {
+		if s.name != item.Name {
+			continue
+		}
+		rng := s.location.Range
+		item.Location.Range = &rng
+		return item, nil
+	}
+
+	return item, nil
+}
+
+// clientSupportsWorkspaceSymbolResolve reports whether the client
+// advertised `workspace.symbol.resolveSupport` for `location.range` in
+// its `initialize` request.
+func clientSupportsWorkspaceSymbolResolve(ctx context.Context) bool {
+	caps, ok := lsctx.ClientCapabilities(ctx)
+	if !ok || caps.Workspace == nil || caps.Workspace.Symbol == nil {
+		return false
+	}
+	resolveSupport := caps.Workspace.Symbol.ResolveSupport
+	if resolveSupport == nil {
+		return false
+	}
+	for _, prop := range resolveSupport.Properties {
+		if prop == workspaceSymbolResolveProperty {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWorkspaceSymbols filters candidates down to those matching
+// query under matcher, sorted by descending score and, for ties, by
+// the shorter (more specific) name. An empty query matches every
+// candidate and is returned in its original, deterministic collection
+// order rather than being scored.
+//
+// minScore filters out any fuzzy/caseSensitive match scoring below
+// it, trimming the low-quality, out-of-order matches that make fuzzy
+// ranking noisy on large workspaces. It does not apply to the exact
+// matcher, whose matches don't carry a meaningful score.
+func matchWorkspaceSymbols(candidates []workspaceSymbol, query string, matcher settings.SymbolMatcher, minScore int) []workspaceSymbol {
+	if query == "" {
+		all := make([]workspaceSymbol, len(candidates))
+		copy(all, candidates)
+		return all
+	}
+
+	type scoredSymbol struct {
+		symbol workspaceSymbol
+		score  int
+	}
+	var matches []scoredSymbol
+
+	if matcher == settings.SymbolMatcherExact {
+		for _, c := range candidates {
+			if strings.Contains(c.name, query) {
+				matches = append(matches, scoredSymbol{c, 0})
+			}
+		}
+	} else {
+		m := fuzzy.NewMatcher(query, matcher == settings.SymbolMatcherCaseSensitive)
+		for _, c := range candidates {
+			score := m.Score(c.name)
+			if score == fuzzy.NoMatch || score < minScore {
+				continue
+			}
+			matches = append(matches, scoredSymbol{c, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return len(matches[i].symbol.name) < len(matches[j].symbol.name)
+	})
+
+	result := make([]workspaceSymbol, len(matches))
+	for i, m := range matches {
+		result[i] = m.symbol
+	}
+	return result
+}
+
+// collectWorkspaceSymbols walks every document currently indexed by
+// the state store and extracts every block, including nested blocks,
+// as workspace symbol candidates.
+func (svc *service) collectWorkspaceSymbols(ctx context.Context) ([]workspaceSymbol, error) {
+	docs, err := svc.stateStore.DocumentStore.ListAllDocuments()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	var symbols []workspaceSymbol
+	for _, doc := range docs {
+		blockSymbols, err := svc.blockSymbolsInDocument(ctx, doc)
+		if err != nil {
+			// A document that fails to parse (e.g. mid-edit) simply
+			// contributes no symbols instead of failing the request.
+			continue
+		}
+		symbols = append(symbols, blockSymbols...)
+	}
+	return symbols, nil
+}
+
+func (svc *service) blockSymbolsInDocument(ctx context.Context, doc *document.Document) ([]workspaceSymbol, error) {
+	f, diags := hclsyntax.ParseConfig(doc.Text, doc.Filename, hcl.InitialPos)
+	if diags.HasErrors() && f == nil {
+		return nil, diags
+	}
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, nil
+	}
+
+	container := moduleContainerName(ctx, doc.Dir.URI)
+
+	var symbols []workspaceSymbol
+	svc.collectBlockSymbols(doc, body.Blocks, container, &symbols)
+	return symbols, nil
+}
+
+// collectBlockSymbols appends a workspaceSymbol for every block in
+// blocks to symbols, then recurses into each block's own nested
+// blocks. Top-level blocks are containered by container (the module
+// path); a nested block is instead containered by its immediate
+// parent block, e.g. a `lifecycle` block nested in `resource
+// "aws_instance" "web"` gets that resource as its containerName
+// rather than the module path.
+func (svc *service) collectBlockSymbols(doc *document.Document, blocks hclsyntax.Blocks, container string, symbols *[]workspaceSymbol) {
+	for _, block := range blocks {
+		*symbols = append(*symbols, workspaceSymbol{
+			name:          blockSymbolName(block),
+			kind:          lsp.SymbolKindClass,
+			containerName: container,
+			deprecated:    svc.isBlockDeprecated(doc.Dir, block),
+			location: lsp.Location{
+				URI:   lsp.DocumentURI(fmt.Sprintf("%s/%s", doc.Dir.URI, doc.Filename)),
+				Range: hclRangeToLSP(block.Range()),
+			},
+		})
+		svc.collectBlockSymbols(doc, block.Body.Blocks, blockSymbolName(block), symbols)
+	}
+}
+
+// isBlockDeprecated reports whether block's schema, as resolved for
+// the module rooted at dir, carries provider, resource, data source,
+// or top-level attribute deprecation metadata from terraform-schema.
+// Blocks with no matching schema (e.g. an unrecognized block type) are
+// never considered deprecated.
+func (svc *service) isBlockDeprecated(dir document.DirHandle, block *hclsyntax.Block) bool {
+	if svc.stateStore.ProviderSchemas == nil {
+		return false
+	}
+	deprecated, err := svc.stateStore.ProviderSchemas.IsBlockDeprecated(dir, block.Type, block.Labels)
+	if err != nil {
+		return false
+	}
+	return deprecated
+}
+
+// moduleContainerName derives the container name advertised on a
+// workspace symbol from the module path of dirURI relative to the
+// workspace root, e.g. `modules/network` for a submodule. It returns
+// an empty string for the root module itself.
+func moduleContainerName(ctx context.Context, dirURI string) string {
+	root, ok := lsctx.RootDirectory(ctx)
+	if !ok || dirURI == root {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(dirURI, root), "/")
+}
+
+// blockSymbolName renders a block as `type "label" "label"...`, e.g.
+// `resource "aws_instance" "web"`.
+func blockSymbolName(block *hclsyntax.Block) string {
+	parts := make([]string, 0, len(block.Labels)+1)
+	parts = append(parts, block.Type)
+	for _, label := range block.Labels {
+		parts = append(parts, strconv.Quote(label))
+	}
+	return strings.Join(parts, " ")
+}
+
+func hclRangeToLSP(rng hcl.Range) lsp.Range {
+	return lsp.Range{
+		Start: lsp.Position{
+			Line:      uint32(rng.Start.Line - 1),
+			Character: uint32(rng.Start.Column - 1),
+		},
+		End: lsp.Position{
+			Line:      uint32(rng.End.Line - 1),
+			Character: uint32(rng.End.Column - 1),
+		},
+	}
+}
@@ -6,13 +6,19 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	lsctx "github.com/opentofu/tofu-ls/internal/context"
 	"github.com/opentofu/tofu-ls/internal/document"
+	"github.com/opentofu/tofu-ls/internal/fuzzy"
 	"github.com/opentofu/tofu-ls/internal/langserver"
+	lsp "github.com/opentofu/tofu-ls/internal/protocol"
+	"github.com/opentofu/tofu-ls/internal/settings"
 	"github.com/opentofu/tofu-ls/internal/state"
 	"github.com/opentofu/tofu-ls/internal/tofu/exec"
 	"github.com/opentofu/tofu-ls/internal/walker"
@@ -180,6 +186,175 @@ func TestLangServer_workspace_symbol_basic(t *testing.T) {
 	}`, tmpDir.URI))
 }
 
+func TestLangServer_workspace_symbol_fuzzy(t *testing.T) {
+	tmpDir := TempDir(t)
+	InitPluginCache(t, tmpDir.Path())
+
+	initializeFiles(t, tmpDir)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"workspace": {
+				"symbol": {}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "provider \"github\" {}",
+			"uri": "%s/first.tf"
+		}
+	}`, tmpDir.URI)})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "provider \"google\" {}",
+			"uri": "%s/second.tf"
+		}
+	}`, tmpDir.URI)})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "myblock \"custom\" {}",
+			"uri": "%s/blah/third.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// "mbcu" is not a prefix or substring of `myblock "custom"` but is
+	// a subsequence of it, out of order relative to any single word
+	// boundary.
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/symbol",
+		ReqParams: `{
+		"query": "mbcu"
+	}`}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 6,
+		"result": [
+			{
+				"location": {
+					"uri": "%s/blah/third.tf",
+					"range": {
+						"start": {"line": 0, "character": 0},
+						"end": {"line": 0, "character": 19}
+					}
+				},
+				"name": "myblock \"custom\"",
+				"kind": 5
+			}
+		]
+	}`, tmpDir.URI))
+
+	// "provgh" should rank `provider "github"` above `provider
+	// "google"`, since "gh" lines up with the start of "github" but
+	// does not occur, in order, anywhere in "google".
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/symbol",
+		ReqParams: `{
+		"query": "provgh"
+	}`}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 7,
+		"result": [
+			{
+				"location": {
+					"uri": "%s/first.tf",
+					"range": {
+						"start": {"line": 0, "character": 0},
+						"end": {"line": 0, "character": 20}
+					}
+				},
+				"name": "provider \"github\"",
+				"kind": 5
+			}
+		]
+	}`, tmpDir.URI))
+
+	// An empty query returns every symbol in the deterministic order
+	// in which they were discovered, not alphabetically.
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/symbol",
+		ReqParams: `{
+		"query": ""
+	}`}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 8,
+		"result": [
+			{
+				"location": {
+					"uri": "%s/first.tf",
+					"range": {
+						"start": {"line": 0, "character": 0},
+						"end": {"line": 0, "character": 20}
+					}
+				},
+				"name": "provider \"github\"",
+				"kind": 5
+			},
+			{
+				"location": {
+					"uri": "%s/second.tf",
+					"range": {
+						"start": {"line": 0, "character": 0},
+						"end": {"line": 0, "character": 20}
+					}
+				},
+				"name": "provider \"google\"",
+				"kind": 5
+			},
+			{
+				"location": {
+					"uri": "%s/blah/third.tf",
+					"range": {
+						"start": {"line": 0, "character": 0},
+						"end": {"line": 0, "character": 19}
+					}
+				},
+				"name": "myblock \"custom\"",
+				"kind": 5
+			}
+		]
+	}`, tmpDir.URI, tmpDir.URI, tmpDir.URI))
+}
+
 func TestLangServer_workspace_symbol_missing(t *testing.T) {
 	tmpDir := TempDir(t)
 	InitPluginCache(t, tmpDir.Path())
@@ -319,3 +494,497 @@ func TestLangServer_workspace_symbol_missing(t *testing.T) {
 		]
 	}`, tmpDir.URI))
 }
+
+func TestLangServer_workspace_symbol_resolve(t *testing.T) {
+	tmpDir := TempDir(t)
+	InitPluginCache(t, tmpDir.Path())
+
+	initializeFiles(t, tmpDir)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"workspace": {
+				"symbol": {
+					"resolveSupport": {
+						"properties": ["location.range"]
+					}
+				}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "provider \"github\" {}",
+			"uri": "%s/first.tf"
+		}
+	}`, tmpDir.URI)})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "myblock \"custom\" {}",
+			"uri": "%s/blah/third.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// A client that advertises resolveSupport for "location.range"
+	// gets back the lightweight 3.17 shape: no range, and a
+	// containerName for symbols outside the root module.
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/symbol",
+		ReqParams: `{
+		"query": "myb"
+	}`}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 6,
+		"result": [
+			{
+				"location": {
+					"uri": "%s/blah/third.tf"
+				},
+				"name": "myblock \"custom\"",
+				"kind": 5,
+				"containerName": "blah"
+			}
+		]
+	}`, tmpDir.URI))
+
+	// workspaceSymbol/resolve fills in the range left out above by
+	// re-parsing the target document on demand.
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspaceSymbol/resolve",
+		ReqParams: fmt.Sprintf(`{
+		"name": "myblock \"custom\"",
+		"kind": 5,
+		"containerName": "blah",
+		"location": {
+			"uri": "%s/blah/third.tf"
+		}
+	}`, tmpDir.URI)}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 7,
+		"result": {
+			"location": {
+				"uri": "%s/blah/third.tf",
+				"range": {
+					"start": {"line": 0, "character": 0},
+					"end": {"line": 0, "character": 19}
+				}
+			},
+			"name": "myblock \"custom\"",
+			"kind": 5,
+			"containerName": "blah"
+		}
+	}`, tmpDir.URI))
+}
+
+func TestLangServer_workspace_symbol_nestedContainerName(t *testing.T) {
+	tmpDir := TempDir(t)
+	InitPluginCache(t, tmpDir.Path())
+
+	initializeFiles(t, tmpDir)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	// Use the resolveSupport-gated lightweight shape so the response
+	// omits ranges, letting this test focus on containerName alone
+	// rather than also having to account for nested HCL byte offsets.
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"workspace": {
+				"symbol": {
+					"resolveSupport": {
+						"properties": ["location.range"]
+					}
+				}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "resource \"aws_instance\" \"web\" {\n  lifecycle {\n    create_before_destroy = true\n  }\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	// The top-level resource block is containered by the module path
+	// (the root module here, so containerName is omitted entirely),
+	// while the nested lifecycle block is containered by its immediate
+	// parent block rather than the module path.
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/symbol",
+		ReqParams: `{
+		"query": ""
+	}`}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 6,
+		"result": [
+			{
+				"location": {
+					"uri": "%s/main.tf"
+				},
+				"name": "resource \"aws_instance\" \"web\"",
+				"kind": 5
+			},
+			{
+				"location": {
+					"uri": "%s/main.tf"
+				},
+				"name": "lifecycle",
+				"kind": 5,
+				"containerName": "resource \"aws_instance\" \"web\""
+			}
+		]
+	}`, tmpDir.URI, tmpDir.URI))
+}
+
+func TestLangServer_workspace_symbol_matcherSetting(t *testing.T) {
+	tmpDir := TempDir(t)
+	InitPluginCache(t, tmpDir.Path())
+
+	initializeFiles(t, tmpDir)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	// A client opting into the "exact" matcher via
+	// initializationOptions should never see the out-of-order fuzzy
+	// match that TestLangServer_workspace_symbol_fuzzy relies on for
+	// this very query.
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"workspace": {
+				"symbol": {}
+			}
+		},
+		"initializationOptions": {
+			"symbolMatcher": "exact"
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "myblock \"custom\" {}",
+			"uri": "%s/blah/third.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/symbol",
+		ReqParams: `{
+		"query": "mbcu"
+	}`}, `{
+		"jsonrpc": "2.0",
+		"id": 6,
+		"result": []
+	}`)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/symbol",
+		ReqParams: `{
+		"query": "custom"
+	}`}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 7,
+		"result": [
+			{
+				"location": {
+					"uri": "%s/blah/third.tf",
+					"range": {
+						"start": {"line": 0, "character": 0},
+						"end": {"line": 0, "character": 19}
+					}
+				},
+				"name": "myblock \"custom\"",
+				"kind": 5
+			}
+		]
+	}`, tmpDir.URI))
+}
+
+func TestLangServer_workspace_symbol_deprecated(t *testing.T) {
+	tmpDir := TempDir(t)
+	InitPluginCache(t, tmpDir.Path())
+
+	initializeFiles(t, tmpDir)
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wc := walker.NewWalkerCollector()
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TofuCalls: &exec.TofuMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Path(): validTfMockCalls(),
+			},
+		},
+		StateStore:      ss,
+		WalkerCollector: wc,
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"workspace": {
+				"symbol": {
+					"tagSupport": {
+						"valueSet": [ 1 ]
+					}
+				}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, tmpDir.URI)})
+	waitForWalkerPath(t, ss, wc, tmpDir)
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	// Stub the "google" provider's schema as deprecated, the way a real
+	// schema obtained from `tofu providers schema -json` would be if
+	// the provider itself carried deprecation metadata upstream. This
+	// exercises isBlockDeprecated against the real ProviderSchemas
+	// lookup rather than asserting on a hand-built workspaceSymbol.
+	ss.ProviderSchemas.MarkBlockDeprecated(tmpDir, "provider", []string{"google"}, true)
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "provider \"github\" {}",
+			"uri": "%s/first.tf"
+		}
+	}`, tmpDir.URI)})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "opentofu",
+			"text": "provider \"google\" {}",
+			"uri": "%s/second.tf"
+		}
+	}`, tmpDir.URI)})
+	waitForAllJobs(t, ss)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/symbol",
+		ReqParams: `{
+		"query": ""
+	}`}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 6,
+		"result": [
+			{
+				"location": {
+					"uri": "%s/first.tf",
+					"range": {
+						"start": {"line": 0, "character": 0},
+						"end": {"line": 0, "character": 20}
+					}
+				},
+				"name": "provider \"github\"",
+				"kind": 5
+			},
+			{
+				"location": {
+					"uri": "%s/second.tf",
+					"range": {
+						"start": {"line": 0, "character": 0},
+						"end": {"line": 0, "character": 20}
+					}
+				},
+				"name": "provider \"google\"",
+				"kind": 5,
+				"tags": [1]
+			}
+		]
+	}`, tmpDir.URI, tmpDir.URI))
+}
+
+func TestMatchWorkspaceSymbols_minScore(t *testing.T) {
+	candidates := []workspaceSymbol{
+		{name: `mc_block "y"`},
+		{name: `myblock "custom"`},
+	}
+
+	m := fuzzy.NewMatcher("mc", false)
+	strongScore := m.Score(candidates[0].name)
+	weakScore := m.Score(candidates[1].name)
+	if weakScore >= strongScore {
+		t.Fatalf("expected %q to score lower than %q for this test to be meaningful, got %d >= %d", candidates[1].name, candidates[0].name, weakScore, strongScore)
+	}
+
+	all := matchWorkspaceSymbols(candidates, "mc", settings.SymbolMatcherFuzzy, 0)
+	if len(all) != 2 {
+		t.Fatalf("expected both candidates to match with no threshold set, got %d", len(all))
+	}
+
+	filtered := matchWorkspaceSymbols(candidates, "mc", settings.SymbolMatcherFuzzy, strongScore)
+	if len(filtered) != 1 || filtered[0].name != candidates[0].name {
+		t.Fatalf("expected only the stronger match to survive a minScore equal to its own score, got %v", filtered)
+	}
+}
+
+// A subsequence match with a large gap between characters can score
+// below zero (fuzzy.Matcher.Score's gap penalty), even though it is a real
+// match, not a NoMatch. The default threshold must not exclude it.
+func TestMatchWorkspaceSymbols_defaultThresholdKeepsNegativeScoreMatches(t *testing.T) {
+	name := "x" + strings.Repeat(".", 60) + "y"
+	candidates := []workspaceSymbol{{name: name}}
+
+	score := fuzzy.NewMatcher("xy", false).Score(name)
+	if score >= 0 {
+		t.Fatalf("expected this candidate to score below zero for this test to be meaningful, got %d", score)
+	}
+
+	matched := matchWorkspaceSymbols(candidates, "xy", settings.SymbolMatcherFuzzy, settings.DefaultSymbolMatcherMinScore)
+	if len(matched) != 1 {
+		t.Fatalf("expected the default threshold to keep a real match with a negative score, got %v", matched)
+	}
+}
+
+func TestDeprecationTags(t *testing.T) {
+	deprecated := workspaceSymbol{name: `provider "google"`, deprecated: true}
+	current := workspaceSymbol{name: `provider "github"`, deprecated: false}
+
+	if tags := deprecationTags(deprecated, true); len(tags) != 1 || tags[0] != lsp.SymbolTagDeprecated {
+		t.Fatalf("expected a deprecated symbol with client tagSupport to carry the Deprecated tag, got %v", tags)
+	}
+	if tags := deprecationTags(deprecated, false); tags != nil {
+		t.Fatalf("expected no tags without client tagSupport, got %v", tags)
+	}
+	if tags := deprecationTags(current, true); tags != nil {
+		t.Fatalf("expected no tags for a symbol that isn't deprecated, got %v", tags)
+	}
+}
+
+func TestClientSupportsDeprecatedTag(t *testing.T) {
+	withTagSupport := lsctx.WithClientCapabilities(context.Background(), &lsp.ClientCapabilities{
+		Workspace: &lsp.WorkspaceClientCapabilities{
+			Symbol: &lsp.WorkspaceSymbolClientCapabilities{
+				TagSupport: &lsp.SymbolTagSupport{
+					ValueSet: []lsp.SymbolTag{lsp.SymbolTagDeprecated},
+				},
+			},
+		},
+	})
+	if !clientSupportsDeprecatedTag(withTagSupport) {
+		t.Fatal("expected tagSupport for the Deprecated value to be detected")
+	}
+
+	missingTagSupport := lsctx.WithClientCapabilities(context.Background(), &lsp.ClientCapabilities{
+		Workspace: &lsp.WorkspaceClientCapabilities{
+			Symbol: &lsp.WorkspaceSymbolClientCapabilities{},
+		},
+	})
+	if clientSupportsDeprecatedTag(missingTagSupport) {
+		t.Fatal("expected no tagSupport to be detected when the client never declared it")
+	}
+
+	noCapabilities := context.Background()
+	if clientSupportsDeprecatedTag(noCapabilities) {
+		t.Fatal("expected no tagSupport to be detected when capabilities were never stored on the context")
+	}
+}
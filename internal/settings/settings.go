@@ -0,0 +1,118 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package settings carries langserver settings that are decoded once
+// from a client's initializationOptions and consulted by request
+// handlers for the lifetime of a session.
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+)
+
+// SymbolMatcher controls how workspace/symbol queries are matched
+// against candidate symbol names.
+type SymbolMatcher string
+
+const (
+	// SymbolMatcherFuzzy scores candidates in which the query occurs
+	// as a subsequence, the way gopls matches workspace symbols. This
+	// is the default.
+	SymbolMatcherFuzzy SymbolMatcher = "fuzzy"
+
+	// SymbolMatcherExact requires the query to occur as a contiguous
+	// substring of the candidate.
+	SymbolMatcherExact SymbolMatcher = "exact"
+
+	// SymbolMatcherCaseSensitive is like SymbolMatcherFuzzy, except
+	// the query is matched case-sensitively.
+	SymbolMatcherCaseSensitive SymbolMatcher = "caseSensitive"
+)
+
+// DefaultSymbolMatcher is used whenever a client does not set
+// symbolMatcher explicitly.
+const DefaultSymbolMatcher = SymbolMatcherFuzzy
+
+// DefaultSymbolMatcherMinScore is used whenever a client does not set
+// symbolMatcherMinScore explicitly. fuzzy.Matcher.Score's gap penalty can
+// drive the score of a legitimate (non-NoMatch) subsequence match
+// below zero, so 0 would silently exclude matches the matcher itself
+// considers real. math.MinInt applies no extra filtering beyond "is
+// this a match at all", preserving the matcher's own notion of a
+// match.
+const DefaultSymbolMatcherMinScore = math.MinInt
+
+// Settings is the subset of langserver settings that request handlers
+// need direct access to.
+type Settings struct {
+	SymbolMatcher SymbolMatcher
+
+	// SymbolMatcherMinScore is the lowest fuzzy/caseSensitive match
+	// score a workspace/symbol candidate may have and still be
+	// returned. Candidates scoring below this are treated as noise
+	// and dropped, even though they technically matched. It has no
+	// effect on the "exact" matcher, which doesn't produce a score.
+	SymbolMatcherMinScore int
+}
+
+// Default returns the Settings used before a client sends any
+// configuration, or for fields a client leaves unset.
+func Default() Settings {
+	return Settings{
+		SymbolMatcher:         DefaultSymbolMatcher,
+		SymbolMatcherMinScore: DefaultSymbolMatcherMinScore,
+	}
+}
+
+// rawOptions is the subset of a client's initializationOptions (or
+// workspace/didChangeConfiguration settings) this package understands.
+type rawOptions struct {
+	SymbolMatcher         *SymbolMatcher `json:"symbolMatcher,omitempty"`
+	SymbolMatcherMinScore *int           `json:"symbolMatcherMinScore,omitempty"`
+}
+
+// Decode parses raw client options into Settings, falling back to
+// Default() for any field that is absent. If raw fails to parse, even
+// for a single field, every field keeps its Default() value. A nil or
+// empty raw is treated the same as a client that set nothing.
+func Decode(raw json.RawMessage) Settings {
+	result := Default()
+	if len(raw) == 0 {
+		return result
+	}
+
+	var opts rawOptions
+	if err := json.Unmarshal(raw, &opts); err != nil {
+		return result
+	}
+
+	if opts.SymbolMatcher != nil {
+		result.SymbolMatcher = *opts.SymbolMatcher
+	}
+	if opts.SymbolMatcherMinScore != nil {
+		result.SymbolMatcherMinScore = *opts.SymbolMatcherMinScore
+	}
+	return result
+}
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying s, for handlers to
+// retrieve via FromContext.
+func WithContext(ctx context.Context, s Settings) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromContext returns the Settings stored in ctx by WithContext, or
+// Default() if none were stored.
+func FromContext(ctx context.Context) Settings {
+	s, ok := ctx.Value(contextKey{}).(Settings)
+	if !ok {
+		return Default()
+	}
+	return s
+}
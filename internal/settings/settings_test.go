@@ -0,0 +1,42 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2024 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package settings
+
+import "testing"
+
+func TestDecode_empty(t *testing.T) {
+	got := Decode(nil)
+	if got != Default() {
+		t.Fatalf("expected Decode(nil) to equal Default(), got %+v", got)
+	}
+}
+
+func TestDecode_symbolMatcher(t *testing.T) {
+	got := Decode([]byte(`{"symbolMatcher": "exact"}`))
+	if got.SymbolMatcher != SymbolMatcherExact {
+		t.Fatalf("expected symbolMatcher to be decoded as %q, got %q", SymbolMatcherExact, got.SymbolMatcher)
+	}
+	if got.SymbolMatcherMinScore != DefaultSymbolMatcherMinScore {
+		t.Fatalf("expected symbolMatcherMinScore to keep its default, got %d", got.SymbolMatcherMinScore)
+	}
+}
+
+func TestDecode_minScore(t *testing.T) {
+	got := Decode([]byte(`{"symbolMatcherMinScore": 12}`))
+	if got.SymbolMatcherMinScore != 12 {
+		t.Fatalf("expected symbolMatcherMinScore to be decoded as 12, got %d", got.SymbolMatcherMinScore)
+	}
+	if got.SymbolMatcher != DefaultSymbolMatcher {
+		t.Fatalf("expected symbolMatcher to keep its default, got %q", got.SymbolMatcher)
+	}
+}
+
+func TestDecode_malformedFallsBackToDefault(t *testing.T) {
+	got := Decode([]byte(`not json`))
+	if got != Default() {
+		t.Fatalf("expected malformed options to fall back to Default(), got %+v", got)
+	}
+}